@@ -0,0 +1,63 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/linqcod/proglog/internal/log"
+)
+
+type catRecord struct {
+	File   string `json:"file"`
+	Offset uint64 `json:"offset"`
+	Pos    uint64 `json:"pos"`
+	Size   int    `json:"size"`
+	Data   []byte `json:"data"`
+}
+
+// runCat function decodes store files directly from disk and prints their records to out
+// does not need a running server, so it can be used on a dead node's data directory
+func runCat(args []string, out io.Writer) error {
+	flags := flag.NewFlagSet("cat", flag.ContinueOnError)
+	asJSON := flags.Bool("json", false, "print one JSON object per record")
+	if err := flags.Parse(args); err != nil {
+		return fmt.Errorf("%w: %v", errUsage, err)
+	}
+
+	if flags.NArg() != 1 {
+		return fmt.Errorf("%w: cat expects exactly one store file or directory", errUsage)
+	}
+
+	files, err := storeFiles(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	if len(files) == 0 {
+		return fmt.Errorf("no %s files in %s", storeFileExt, flags.Arg(0))
+	}
+
+	encoder := json.NewEncoder(out)
+	for _, file := range files {
+		// offset of a record is the file's base offset plus its index within the file
+		// files without a base offset in their name are counted from zero
+		offset, _ := baseOffset(file)
+
+		_, err = log.ScanStore(file, func(pos uint64, data []byte) error {
+			defer func() { offset++ }()
+
+			if *asJSON {
+				return encoder.Encode(catRecord{File: file, Offset: offset, Pos: pos, Size: len(data), Data: data})
+			}
+
+			_, err := fmt.Fprintf(out, "%s\toffset=%d\tpos=%d\tsize=%d\t%q\n", file, offset, pos, len(data), data)
+			return err
+		})
+		if err != nil {
+			return fmt.Errorf("%s: %w", file, err)
+		}
+	}
+
+	return nil
+}
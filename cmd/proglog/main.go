@@ -0,0 +1,121 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const storeFileExt = ".store"
+
+// errUsage is wrapped by command errors caused by wrong arguments
+var errUsage = errors.New("invalid usage")
+
+// exit codes of the proglog binary, verify relies on them for automated checks
+const (
+	exitFailure    = 1
+	exitUsage      = 2
+	exitCorrupt    = 3
+	exitUnreadable = 4
+)
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(exitUsage)
+	}
+
+	var err error
+	switch os.Args[1] {
+	case "cat":
+		err = runCat(os.Args[2:], os.Stdout)
+	case "verify":
+		err = runVerify(os.Args[2:], os.Stdout)
+	default:
+		usage()
+		os.Exit(exitUsage)
+	}
+
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "proglog:", err)
+		os.Exit(exitCode(err))
+	}
+}
+
+// exitCode function maps an error returned by a command to the process exit code
+func exitCode(err error) int {
+	switch {
+	case err == nil:
+		return 0
+	case errors.Is(err, errUsage):
+		return exitUsage
+	case errors.Is(err, errCorrupt):
+		return exitCorrupt
+	case errors.Is(err, errUnreadable):
+		return exitUnreadable
+	default:
+		return exitFailure
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: proglog <command> [flags] <store-file-or-dir>")
+	fmt.Fprintln(os.Stderr, "")
+	fmt.Fprintln(os.Stderr, "commands:")
+	fmt.Fprintln(os.Stderr, "  cat     print records of store files")
+	fmt.Fprintln(os.Stderr, "  verify  validate store files and print a JSON report")
+}
+
+// storeFiles function returns path itself if it is a file
+// or all store files inside path if it is a directory, ordered by base offset
+// files not named <baseOffset>.store go last, ordered by name
+// a directory without store files gives an empty list
+func storeFiles(path string) ([]string, error) {
+	fileInfo, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if !fileInfo.IsDir() {
+		return []string{path}, nil
+	}
+
+	files, err := filepath.Glob(filepath.Join(path, "*"+storeFileExt))
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(files, func(i, j int) bool {
+		iOffset, iOK := baseOffset(files[i])
+		jOffset, jOK := baseOffset(files[j])
+		switch {
+		case iOK && jOK:
+			return iOffset < jOffset
+		case iOK != jOK:
+			return iOK
+		default:
+			return files[i] < files[j]
+		}
+	})
+
+	return files, nil
+}
+
+// baseOffset function parses the base offset of a <baseOffset>.store file name
+// returns false if the name doesn't follow that pattern
+func baseOffset(file string) (uint64, bool) {
+	name := filepath.Base(file)
+	if !strings.HasSuffix(name, storeFileExt) {
+		return 0, false
+	}
+
+	offset, err := strconv.ParseUint(strings.TrimSuffix(name, storeFileExt), 10, 64)
+	if err != nil {
+		return 0, false
+	}
+
+	return offset, true
+}
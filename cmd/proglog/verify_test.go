@@ -0,0 +1,118 @@
+package main
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifyClean(t *testing.T) {
+	dir := t.TempDir()
+	file := writeStore(t, filepath.Join(dir, "0.store"), testRecords...)
+
+	report, code := verify(t, dir)
+	require.Equal(t, 0, code)
+	require.True(t, report.OK)
+	require.Equal(t, []verifyFileReport{
+		{File: file, Status: verifyStatusOK, Records: 2, ValidBytes: 8 + 5 + 8 + 6},
+	}, report.Files)
+}
+
+func TestVerifyTruncated(t *testing.T) {
+	dir := t.TempDir()
+	clean := writeStore(t, filepath.Join(dir, "0.store"), testRecords...)
+	truncated := writeStore(t, filepath.Join(dir, "2.store"), testRecords...)
+	require.NoError(t, os.Truncate(truncated, 8+5+8+3))
+
+	report, code := verify(t, dir)
+	require.Equal(t, exitCorrupt, code)
+	require.False(t, report.OK)
+	require.Len(t, report.Files, 2)
+	require.Equal(t, verifyFileReport{File: clean, Status: verifyStatusOK, Records: 2, ValidBytes: 8 + 5 + 8 + 6}, report.Files[0])
+
+	fileReport := report.Files[1]
+	require.Equal(t, truncated, fileReport.File)
+	require.Equal(t, verifyStatusCorrupt, fileReport.Status)
+	require.Equal(t, 1, fileReport.Records)
+	require.Equal(t, uint64(8+5), fileReport.ValidBytes)
+	require.Contains(t, fileReport.Error, "at pos 13")
+}
+
+func TestVerifyLengthTooLarge(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "0.store")
+
+	var b bytes.Buffer
+	require.NoError(t, binary.Write(&b, binary.BigEndian, uint64(1<<40)))
+	b.WriteString("tiny")
+	require.NoError(t, os.WriteFile(file, b.Bytes(), 0644))
+
+	report, code := verify(t, file)
+	require.Equal(t, exitCorrupt, code)
+	require.Len(t, report.Files, 1)
+	require.Equal(t, verifyStatusCorrupt, report.Files[0].Status)
+	require.Equal(t, 0, report.Files[0].Records)
+	require.Equal(t, uint64(0), report.Files[0].ValidBytes)
+	require.Contains(t, report.Files[0].Error, "exceeds file size")
+}
+
+func TestVerifyUnreadable(t *testing.T) {
+	dir := t.TempDir()
+	clean := writeStore(t, filepath.Join(dir, "0.store"), testRecords...)
+	// a directory with a store name isn't a regular file, so it can't be scanned
+	unreadable := filepath.Join(dir, "2.store")
+	require.NoError(t, os.Mkdir(unreadable, 0755))
+
+	report, code := verify(t, dir)
+	require.Equal(t, exitUnreadable, code)
+	require.False(t, report.OK)
+	require.Len(t, report.Files, 2)
+	require.Equal(t, clean, report.Files[0].File)
+	require.Equal(t, verifyStatusOK, report.Files[0].Status)
+	require.Equal(t, unreadable, report.Files[1].File)
+	require.Equal(t, verifyStatusUnreadable, report.Files[1].Status)
+	require.NotEmpty(t, report.Files[1].Error)
+}
+
+func TestVerifyEmptyDir(t *testing.T) {
+	var out bytes.Buffer
+	err := runVerify([]string{t.TempDir()}, &out)
+	require.NoError(t, err)
+	require.Equal(t, 0, exitCode(err))
+	require.JSONEq(t, `{"ok": true, "files": []}`, out.String())
+}
+
+func TestVerifyUsage(t *testing.T) {
+	var out bytes.Buffer
+	err := runVerify(nil, &out)
+	require.ErrorIs(t, err, errUsage)
+	require.Equal(t, exitUsage, exitCode(err))
+}
+
+func TestVerifyBadFlag(t *testing.T) {
+	for _, args := range [][]string{{"-bogus", "."}, {"-h"}} {
+		var out bytes.Buffer
+		err := runVerify(args, &out)
+		require.ErrorIs(t, err, errUsage)
+		require.Equal(t, exitUsage, exitCode(err))
+		require.Empty(t, out.String())
+	}
+}
+
+// verify function runs the verify command on path
+// returns the decoded report and the exit code the binary would use
+func verify(t *testing.T, path string) (verifyReport, int) {
+	t.Helper()
+
+	var out bytes.Buffer
+	err := runVerify([]string{path}, &out)
+
+	var report verifyReport
+	require.NoError(t, json.Unmarshal(out.Bytes(), &report))
+
+	return report, exitCode(err)
+}
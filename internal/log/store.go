@@ -3,23 +3,39 @@ package log
 import (
 	"bufio"
 	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
 	"os"
 	"sync"
 )
 
 var enc = binary.BigEndian
 
+// ErrTruncatedRecord is returned when a store file ends in the middle of a record
+var ErrTruncatedRecord = errors.New("truncated record")
+
 const dataLengthWeightInBytes = 8
 
+// fsWrapper is the set of file operations the store performs on its file
+// *os.File satisfies it, tests wrap it to inject faults like short writes or ENOSPC
+type fsWrapper interface {
+	io.Writer
+	io.ReaderAt
+	io.Closer
+	Name() string
+	Stat() (os.FileInfo, error)
+}
+
 type store struct {
-	*os.File
+	fsWrapper
 	mutex    sync.Mutex
 	buffer   *bufio.Writer
 	fileSize uint64
 }
 
-func newStore(file *os.File) (*store, error) {
-	fileInfo, err := os.Stat(file.Name())
+func newStore(file fsWrapper) (*store, error) {
+	fileInfo, err := file.Stat()
 	if err != nil {
 		return nil, err
 	}
@@ -27,9 +43,9 @@ func newStore(file *os.File) (*store, error) {
 	fileSize := uint64(fileInfo.Size())
 
 	return &store{
-		File:     file,
-		fileSize: fileSize,
-		buffer:   bufio.NewWriter(file),
+		fsWrapper: file,
+		fileSize:  fileSize,
+		buffer:    bufio.NewWriter(file),
 	}, nil
 }
 
@@ -73,13 +89,13 @@ func (s *store) Read(pos uint64) ([]byte, error) {
 
 	length := make([]byte, dataLengthWeightInBytes)
 	// reading length of the log data starting at pos
-	if _, err := s.File.ReadAt(length, int64(pos)); err != nil {
+	if _, err := s.fsWrapper.ReadAt(length, int64(pos)); err != nil {
 		return nil, err
 	}
 
 	b := make([]byte, enc.Uint64(length))
 	// reading log data with size of length starting from pos + dataLengthWeightInBytes
-	if _, err := s.File.ReadAt(b, int64(pos+dataLengthWeightInBytes)); err != nil {
+	if _, err := s.fsWrapper.ReadAt(b, int64(pos+dataLengthWeightInBytes)); err != nil {
 		return nil, err
 	}
 
@@ -97,7 +113,7 @@ func (s *store) ReadAt(data []byte, offset int64) (int, error) {
 		return 0, err
 	}
 
-	return s.File.ReadAt(data, offset)
+	return s.fsWrapper.ReadAt(data, offset)
 }
 
 func (s *store) Close() error {
@@ -105,9 +121,71 @@ func (s *store) Close() error {
 	defer s.mutex.Unlock()
 
 	// flushing any data from buffer to store file if it is not already there
-	if err := s.buffer.Flush(); err != nil {
-		return err
+	// the file is closed even if flushing failed, so its descriptor isn't leaked
+	flushErr := s.buffer.Flush()
+	closeErr := s.fsWrapper.Close()
+	if flushErr != nil {
+		return flushErr
 	}
 
-	return s.File.Close()
-}
\ No newline at end of file
+	return closeErr
+}
+
+// ScanStore function reads the store file with the given name record by record without opening a store
+// calls fn with start position and data of every record, stops at the first error returned by fn
+// returns the position right after the last complete record and error
+func ScanStore(name string, fn func(pos uint64, data []byte) error) (uint64, error) {
+	file, err := os.Open(name)
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+
+	fileInfo, err := file.Stat()
+	if err != nil {
+		return 0, err
+	}
+	// directories and other special files report filesystem dependent sizes, so they can't be scanned reliably
+	if !fileInfo.Mode().IsRegular() {
+		return 0, fmt.Errorf("%s is not a regular file", name)
+	}
+	fileSize := uint64(fileInfo.Size())
+
+	reader := bufio.NewReader(file)
+	length := make([]byte, dataLengthWeightInBytes)
+
+	var pos uint64
+	for pos < fileSize {
+		// reading length of the log data starting at pos
+		if _, err := io.ReadFull(reader, length); err != nil {
+			return pos, truncatedAt(pos, err)
+		}
+
+		// checking length against the rest of the file so a corrupted length can't cause a huge allocation
+		size := enc.Uint64(length)
+		if size > fileSize-pos-dataLengthWeightInBytes {
+			return pos, fmt.Errorf("%w at pos %d: length %d exceeds file size", ErrTruncatedRecord, pos, size)
+		}
+
+		b := make([]byte, size)
+		if _, err := io.ReadFull(reader, b); err != nil {
+			return pos, truncatedAt(pos, err)
+		}
+
+		if err := fn(pos, b); err != nil {
+			return pos, err
+		}
+
+		pos += dataLengthWeightInBytes + size
+	}
+
+	return pos, nil
+}
+
+func truncatedAt(pos uint64, err error) error {
+	if errors.Is(err, io.ErrUnexpectedEOF) {
+		return fmt.Errorf("%w at pos %d", ErrTruncatedRecord, pos)
+	}
+
+	return err
+}
@@ -2,7 +2,9 @@ package log
 
 import (
 	"github.com/stretchr/testify/require"
+	"io"
 	"os"
+	"syscall"
 	"testing"
 )
 
@@ -93,6 +95,153 @@ func TestStoreClose(t *testing.T) {
 	require.True(t, afterSize > beforeSize)
 }
 
+func TestScanStore(t *testing.T) {
+	f, err := os.CreateTemp("", "scan_store_test")
+	require.NoError(t, err)
+	defer os.Remove(f.Name())
+
+	s, err := newStore(f)
+	require.NoError(t, err)
+
+	testAppend(t, s)
+	require.NoError(t, s.Close())
+
+	var positions []uint64
+	end, err := ScanStore(f.Name(), func(pos uint64, data []byte) error {
+		require.Equal(t, testData, data)
+		positions = append(positions, pos)
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, []uint64{0, testDataLength, testDataLength * 2}, positions)
+	require.Equal(t, testDataLength*3, end)
+
+	// cutting the last record in half
+	err = os.Truncate(f.Name(), int64(testDataLength*3-2))
+	require.NoError(t, err)
+
+	positions = nil
+	end, err = ScanStore(f.Name(), func(pos uint64, data []byte) error {
+		positions = append(positions, pos)
+		return nil
+	})
+	require.ErrorIs(t, err, ErrTruncatedRecord)
+	require.Equal(t, []uint64{0, testDataLength}, positions)
+	require.Equal(t, testDataLength*2, end)
+}
+
+func TestScanStoreNotRegular(t *testing.T) {
+	dir, err := os.MkdirTemp("", "scan_store_dir_test")
+	require.NoError(t, err)
+	defer os.Remove(dir)
+
+	_, err = ScanStore(dir, func(pos uint64, data []byte) error {
+		return nil
+	})
+	require.ErrorContains(t, err, "not a regular file")
+}
+
+// faultyFile wraps a real file and fails or shortens its operations on demand
+type faultyFile struct {
+	*os.File
+	statErr    error
+	writeErr   error
+	shortWrite bool
+	closed     bool
+}
+
+func (f *faultyFile) Stat() (os.FileInfo, error) {
+	if f.statErr != nil {
+		return nil, f.statErr
+	}
+
+	return f.File.Stat()
+}
+
+func (f *faultyFile) Write(p []byte) (int, error) {
+	if f.writeErr != nil {
+		return 0, f.writeErr
+	}
+	if f.shortWrite && len(p) > 0 {
+		return f.File.Write(p[:len(p)-1])
+	}
+
+	return f.File.Write(p)
+}
+
+func (f *faultyFile) Close() error {
+	f.closed = true
+	return f.File.Close()
+}
+
+func TestStoreStatFault(t *testing.T) {
+	f, err := os.CreateTemp("", "store_stat_fault_test")
+	require.NoError(t, err)
+	defer os.Remove(f.Name())
+	defer f.Close()
+
+	_, err = newStore(&faultyFile{File: f, statErr: syscall.EIO})
+	require.ErrorIs(t, err, syscall.EIO)
+}
+
+func TestStoreWriteFaults(t *testing.T) {
+	t.Run("no space left on device", func(t *testing.T) {
+		f, err := os.CreateTemp("", "store_enospc_test")
+		require.NoError(t, err)
+		defer os.Remove(f.Name())
+
+		file := &faultyFile{File: f, writeErr: syscall.ENOSPC}
+		testWriteFault(t, file, syscall.ENOSPC)
+
+		// nothing reached the disk, so recovery sees an empty store
+		var records int
+		end, err := ScanStore(f.Name(), func(pos uint64, data []byte) error {
+			records++
+			return nil
+		})
+		require.NoError(t, err)
+		require.Equal(t, uint64(0), end)
+		require.Equal(t, 0, records)
+	})
+
+	t.Run("short write", func(t *testing.T) {
+		f, err := os.CreateTemp("", "store_short_write_test")
+		require.NoError(t, err)
+		defer os.Remove(f.Name())
+
+		file := &faultyFile{File: f, shortWrite: true}
+		testWriteFault(t, file, io.ErrShortWrite)
+
+		// the record reached the disk without its last byte and must be detected as truncated
+		end, err := ScanStore(f.Name(), func(pos uint64, data []byte) error {
+			return nil
+		})
+		require.ErrorIs(t, err, ErrTruncatedRecord)
+		require.ErrorContains(t, err, "at pos 0")
+		require.Equal(t, uint64(0), end)
+	})
+}
+
+// testWriteFault function appends to a store over a faulty file and checks the fault surfaces on flush
+// also checks that the file gets closed even though the final flush fails
+func testWriteFault(t *testing.T, file *faultyFile, wantErr error) {
+	t.Helper()
+
+	s, err := newStore(file)
+	require.NoError(t, err)
+
+	// data stays in the buffer, so the fault shows up on the first flush
+	_, _, err = s.Append(testData)
+	require.NoError(t, err)
+
+	_, err = s.Read(0)
+	require.ErrorIs(t, err, wantErr)
+
+	err = s.Close()
+	require.ErrorIs(t, err, wantErr)
+	require.True(t, file.closed)
+}
+
 func openFile(name string) (file *os.File, size int64, err error) {
 	f, err := os.OpenFile(name, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0644)
 	if err != nil {